@@ -0,0 +1,190 @@
+package document
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"os"
+)
+
+// addFile writes a file entry to the ZIP archive.
+func addFile(zipWriter *zip.Writer, name, content string) error {
+	writer, err := zipWriter.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = writer.Write([]byte(content))
+	return err
+}
+
+// addBytes writes a binary file entry to the ZIP archive.
+func addBytes(zipWriter *zip.Writer, name string, content []byte) error {
+	writer, err := zipWriter.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = writer.Write(content)
+	return err
+}
+
+// addDir creates a directory entry in the ZIP archive.
+func addDir(zipWriter *zip.Writer, name string) error {
+	header := &zip.FileHeader{
+		Name:   name,
+		Method: zip.Store,
+	}
+	header.SetMode(0755 | os.ModeDir)
+	_, err := zipWriter.CreateHeader(header)
+	return err
+}
+
+// Build renders doc to a DOCX file (ZIP archive) at outputPath, using doc's
+// Options for page size, orientation, and margins.
+func Build(doc *Document, outputPath string) error {
+	options := doc.Options
+	pageWidthTwips, pageHeightTwips := options.pageDimsTwips()
+	margins := options.Margins
+
+	r := newRenderer(options.usableWidthEmu())
+	body, err := r.renderBody(doc.Blocks)
+	if err != nil {
+		return err
+	}
+
+	documentXML := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"
+            xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"
+            xmlns:wp="http://schemas.openxmlformats.org/drawingml/2006/wordprocessingDrawing"
+            xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main"
+            xmlns:pic="http://schemas.openxmlformats.org/drawingml/2006/picture"
+            xmlns:asvg="http://schemas.microsoft.com/office/drawing/2016/SVG/main">
+  <w:body>%s
+    <w:sectPr>
+      <w:pgSz w:w="%d" w:h="%d"/>
+      <w:pgMar w:top="%d" w:right="%d" w:bottom="%d" w:left="%d"/>
+    </w:sectPr>
+  </w:body>
+</w:document>`, body, pageWidthTwips, pageHeightTwips, margins.Top, margins.Right, margins.Bottom, margins.Left)
+
+	docRels := documentRelsXML(r)
+	contentTypes := contentTypesXML(r.lib)
+
+	const rootRels = `<?xml version="1.0" encoding="UTF-8"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+    <Relationship Id="rId1"
+        Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument"
+        Target="word/document.xml"/>
+</Relationships>`
+
+	outFile, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("error creating output file: %v", err)
+	}
+	defer outFile.Close()
+
+	zipWriter := zip.NewWriter(outFile)
+	defer zipWriter.Close()
+
+	if err := addDir(zipWriter, "_rels/"); err != nil {
+		return fmt.Errorf("error adding _rels/ directory: %v", err)
+	}
+	if err := addDir(zipWriter, "word/"); err != nil {
+		return fmt.Errorf("error adding word/ directory: %v", err)
+	}
+	if err := addDir(zipWriter, "word/_rels/"); err != nil {
+		return fmt.Errorf("error adding word/_rels/ directory: %v", err)
+	}
+	if err := addDir(zipWriter, "word/media/"); err != nil {
+		return fmt.Errorf("error adding word/media/ directory: %v", err)
+	}
+
+	if err := addFile(zipWriter, "[Content_Types].xml", contentTypes); err != nil {
+		return fmt.Errorf("error adding [Content_Types].xml: %v", err)
+	}
+	if err := addFile(zipWriter, "_rels/.rels", rootRels); err != nil {
+		return fmt.Errorf("error adding _rels/.rels: %v", err)
+	}
+	if err := addFile(zipWriter, "word/document.xml", documentXML); err != nil {
+		return fmt.Errorf("error adding word/document.xml: %v", err)
+	}
+	if err := addFile(zipWriter, "word/_rels/document.xml.rels", docRels); err != nil {
+		return fmt.Errorf("error adding word/_rels/document.xml.rels: %v", err)
+	}
+	if err := addFile(zipWriter, "word/styles.xml", stylesXML()); err != nil {
+		return fmt.Errorf("error adding word/styles.xml: %v", err)
+	}
+	if err := addFile(zipWriter, "word/numbering.xml", numberingXML()); err != nil {
+		return fmt.Errorf("error adding word/numbering.xml: %v", err)
+	}
+
+	for i, asset := range r.lib.assets {
+		assetIndex := i + 1
+		partName := fmt.Sprintf("word/media/image%d.%s", assetIndex, asset.format.Extension)
+		if err := addBytes(zipWriter, partName, asset.data); err != nil {
+			return fmt.Errorf("error adding %q: %v", partName, err)
+		}
+		if asset.svgData != nil {
+			svgName := fmt.Sprintf("word/media/image%d.svg", assetIndex)
+			if err := addBytes(zipWriter, svgName, asset.svgData); err != nil {
+				return fmt.Errorf("error adding %q: %v", svgName, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func documentRelsXML(r *renderer) string {
+	var buf bytes.Buffer
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+    <Relationship Id="rIdStyles"
+        Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles"
+        Target="styles.xml"/>
+    <Relationship Id="rIdNumbering"
+        Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/numbering"
+        Target="numbering.xml"/>`)
+	for i, asset := range r.lib.assets {
+		assetIndex := i + 1
+		buf.WriteString(fmt.Sprintf(`
+    <Relationship Id="%s"
+        Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/image"
+        Target="media/image%d.%s"/>`, asset.relID, assetIndex, asset.format.Extension))
+		if asset.svgData != nil {
+			buf.WriteString(fmt.Sprintf(`
+    <Relationship Id="%s"
+        Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/image"
+        Target="media/image%d.svg"/>`, asset.svgRelID, assetIndex))
+		}
+	}
+	for _, h := range r.hyperlinks {
+		buf.WriteString(fmt.Sprintf(`
+    <Relationship Id="%s"
+        Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/hyperlink"
+        Target="%s" TargetMode="External"/>`, h.id, h.url))
+	}
+	buf.WriteString("\n</Relationships>")
+	return buf.String()
+}
+
+func contentTypesXML(lib *mediaLibrary) string {
+	var buf bytes.Buffer
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+    <Default Extension="xml" ContentType="application/xml"/>
+    <Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+    <Override PartName="/word/document.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"/>
+    <Override PartName="/word/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.styles+xml"/>
+    <Override PartName="/word/numbering.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.numbering+xml"/>`)
+	for i, asset := range lib.assets {
+		assetIndex := i + 1
+		buf.WriteString(fmt.Sprintf(`
+    <Override PartName="/word/media/image%d.%s" ContentType="%s"/>`, assetIndex, asset.format.Extension, asset.format.ContentType))
+		if asset.svgData != nil {
+			buf.WriteString(fmt.Sprintf(`
+    <Override PartName="/word/media/image%d.svg" ContentType="image/svg+xml"/>`, assetIndex))
+		}
+	}
+	buf.WriteString("\n</Types>")
+	return buf.String()
+}
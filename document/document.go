@@ -0,0 +1,173 @@
+// Package document provides a structured DOCX document model: a small AST of
+// headings, paragraphs, lists, tables, hyperlinks and images that is rendered
+// to Office Open XML by Build. It replaces the earlier flat "figure"/"text"
+// entry scheme with something that can express real reports.
+package document
+
+// Block is any top-level element that can appear in a Document's body:
+// Paragraph, Heading, List, Table, or Image.
+type Block interface {
+	isBlock()
+}
+
+// Document is an ordered sequence of blocks, rendered using Options' page
+// geometry.
+type Document struct {
+	Blocks  []Block
+	Options DocxOptions
+}
+
+// New creates an empty Document. By default it uses DefaultDocxOptions;
+// passing an options value overrides the page geometry used when it is
+// rendered by Build.
+func New(opts ...DocxOptions) *Document {
+	options := DefaultDocxOptions()
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+	return &Document{Options: options}
+}
+
+// AddHeading appends a heading at the given level (1-6) and returns it so
+// callers can continue to adjust it.
+func (d *Document) AddHeading(level int, text string) *Heading {
+	h := &Heading{Level: level, Text: text}
+	d.Blocks = append(d.Blocks, h)
+	return h
+}
+
+// AddParagraph appends a paragraph made of the given runs and returns it.
+func (d *Document) AddParagraph(runs ...Run) *Paragraph {
+	p := &Paragraph{Runs: runs}
+	d.Blocks = append(d.Blocks, p)
+	return p
+}
+
+// AddText is a convenience for the common case of a paragraph holding a
+// single unstyled run.
+func (d *Document) AddText(text string) *Paragraph {
+	return d.AddParagraph(Run{Text: text})
+}
+
+// AddList appends a list (ordered or unordered) and returns it.
+func (d *Document) AddList(ordered bool, items ...ListItem) *List {
+	l := &List{Ordered: ordered, Items: items}
+	d.Blocks = append(d.Blocks, l)
+	return l
+}
+
+// AddTable appends a table and returns it.
+func (d *Document) AddTable(rows ...TableRow) *Table {
+	t := &Table{Rows: rows}
+	d.Blocks = append(d.Blocks, t)
+	return t
+}
+
+// AddImage appends an image (with an optional legend paragraph rendered
+// immediately after it, matching the old figure/legend pairing) and returns
+// the image block.
+func (d *Document) AddImage(img Image) *Image {
+	d.Blocks = append(d.Blocks, &img)
+	if img.Legend != "" {
+		d.AddText(img.Legend)
+	}
+	return &img
+}
+
+// Run is a span of text with direct character formatting, the DOCX
+// equivalent of a w:r/w:rPr pair.
+type Run struct {
+	Text           string
+	Bold           bool
+	Italic         bool
+	Underline      bool
+	Color          string // hex RGB, e.g. "FF0000"; empty means inherit
+	SizeHalfPoints int    // w:sz is in half-points; 0 means inherit
+}
+
+// Hyperlink is a run of text rendered as an external hyperlink.
+type Hyperlink struct {
+	Text string
+	URL  string
+}
+
+// Paragraph is a block of one or more runs and/or hyperlinks rendered on
+// their own line.
+type Paragraph struct {
+	Runs       []Run
+	Hyperlinks []Hyperlink
+}
+
+func (*Paragraph) isBlock() {}
+
+// Heading is a paragraph styled with one of the built-in Heading1-6 styles.
+// Level is clamped to [1,6] by the renderer.
+type Heading struct {
+	Level int
+	Text  string
+}
+
+func (*Heading) isBlock() {}
+
+// ListItem is one entry in a List. Children nest the item as a sub-list one
+// level deeper, backed by w:numPr/w:ilvl in numbering.xml.
+type ListItem struct {
+	Text     string
+	Children []ListItem
+}
+
+// List is an ordered or unordered list, backed by a numbering definition in
+// word/numbering.xml.
+type List struct {
+	Ordered bool
+	Items   []ListItem
+}
+
+func (*List) isBlock() {}
+
+// TableCell is one cell of a TableRow.
+type TableCell struct {
+	Text       string
+	WidthTwips int // 0 means split the row width evenly
+}
+
+// TableRow is one row of a Table.
+type TableRow struct {
+	Cells []TableCell
+}
+
+// Table is a grid of rows and cells, backed by w:tbl/w:tblPr.
+type Table struct {
+	Rows    []TableRow
+	Borders bool
+}
+
+func (*Table) isBlock() {}
+
+// Image is a figure: an image file plus placement and sizing hints. Legend,
+// if set, is rendered as a plain paragraph immediately following the image.
+type Image struct {
+	Path   string
+	Legend string
+
+	// WidthEmu/HeightEmu pin an exact size. If zero, the size is derived
+	// from the image's own dimensions (SVG viewBox or raster pixel size),
+	// scaled to fit MaxWidthEmu (or the page's usable width if that is also
+	// zero).
+	WidthEmu    int
+	HeightEmu   int
+	MaxWidthEmu int
+
+	Alignment Alignment
+}
+
+func (*Image) isBlock() {}
+
+// Alignment is the horizontal alignment of a block such as an Image.
+type Alignment int
+
+const (
+	AlignLeft Alignment = iota
+	AlignCenter
+	AlignRight
+)
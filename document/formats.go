@@ -0,0 +1,144 @@
+package document
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	_ "image/gif"  // registers image/gif with image.DecodeConfig
+	_ "image/jpeg" // registers image/jpeg with image.DecodeConfig
+	_ "image/png"  // registers image/png with image.DecodeConfig
+	"net/http"
+)
+
+// ImageFormat describes how one raster image MIME type is embedded: the file
+// extension and Content_Types.xml content type used for its word/media part,
+// and how to recover its pixel dimensions so an EMU extent can be computed
+// (pixels x 9525 EMU/px at 96 DPI).
+type ImageFormat struct {
+	Extension   string
+	ContentType string
+	DecodeSize  func(data []byte) (widthPx, heightPx int, err error)
+}
+
+// ImageFormats is keyed by MIME type and holds the raster formats Image
+// figures may be written in. It ships PNG, JPEG, GIF, and TIFF; register
+// additional entries (e.g. "image/bmp", "image/webp") before calling Build to
+// support more. SVG is handled separately (parseSVGGeometry reads its size,
+// mediaLibrary.add embeds it), since it is embedded as both a rasterized PNG
+// fallback and the original vector source rather than as a single raster part.
+var ImageFormats = map[string]ImageFormat{
+	"image/png":  {Extension: "png", ContentType: "image/png", DecodeSize: decodeConfigSize},
+	"image/jpeg": {Extension: "jpg", ContentType: "image/jpeg", DecodeSize: decodeConfigSize},
+	"image/gif":  {Extension: "gif", ContentType: "image/gif", DecodeSize: decodeConfigSize},
+	"image/tiff": {Extension: "tiff", ContentType: "image/tiff", DecodeSize: decodeTIFFSize},
+}
+
+// decodeConfigSize recovers pixel dimensions via image.DecodeConfig, relying
+// on the blank-imported codecs above to have registered the relevant format.
+func decodeConfigSize(data []byte) (widthPx, heightPx int, err error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return 0, 0, err
+	}
+	return cfg.Width, cfg.Height, nil
+}
+
+// decodeTIFFSize reads just enough of a TIFF file's image file directory to
+// recover the ImageWidth (tag 256) and ImageLength (tag 257) entries. The
+// standard library has no TIFF decoder, and pulling in
+// golang.org/x/image/tiff just to learn two integers would be a heavy
+// dependency, so this reads the IFD directly.
+func decodeTIFFSize(data []byte) (widthPx, heightPx int, err error) {
+	if len(data) < 8 {
+		return 0, 0, fmt.Errorf("tiff: file too short")
+	}
+	var order binary.ByteOrder
+	switch {
+	case bytes.HasPrefix(data, []byte("II")):
+		order = binary.LittleEndian
+	case bytes.HasPrefix(data, []byte("MM")):
+		order = binary.BigEndian
+	default:
+		return 0, 0, fmt.Errorf("tiff: missing byte-order marker")
+	}
+
+	ifdOffset := order.Uint32(data[4:8])
+	if int(ifdOffset)+2 > len(data) {
+		return 0, 0, fmt.Errorf("tiff: IFD offset out of range")
+	}
+	entryCount := int(order.Uint16(data[ifdOffset : ifdOffset+2]))
+
+	for i := 0; i < entryCount; i++ {
+		entryStart := int(ifdOffset) + 2 + i*12
+		if entryStart+12 > len(data) {
+			break
+		}
+		tag := order.Uint16(data[entryStart : entryStart+2])
+		fieldType := order.Uint16(data[entryStart+2 : entryStart+4])
+		valueStart := entryStart + 8
+
+		var value uint32
+		if fieldType == 3 { // SHORT: 2 bytes, left-justified in the 4-byte slot
+			value = uint32(order.Uint16(data[valueStart : valueStart+2]))
+		} else { // LONG and other 4-byte-or-smaller types
+			value = order.Uint32(data[valueStart : valueStart+4])
+		}
+
+		switch tag {
+		case 256:
+			widthPx = int(value)
+		case 257:
+			heightPx = int(value)
+		}
+	}
+
+	if widthPx == 0 || heightPx == 0 {
+		return 0, 0, fmt.Errorf("tiff: ImageWidth/ImageLength tag not found")
+	}
+	return widthPx, heightPx, nil
+}
+
+// sniffImageMIME identifies data's image MIME type. It special-cases TIFF,
+// which net/http.DetectContentType does not recognize, then falls back to
+// DetectContentType for the other registered raster formats, then checks for
+// an SVG root element (SVG has no fixed magic number of its own). Content
+// that matches none of these is rejected rather than assumed to be SVG.
+func sniffImageMIME(data []byte) (string, error) {
+	if isTIFF(data) {
+		return "image/tiff", nil
+	}
+	if ct := http.DetectContentType(data); ct != "" {
+		if _, ok := ImageFormats[ct]; ok {
+			return ct, nil
+		}
+	}
+	if looksLikeSVG(data) {
+		return "image/svg+xml", nil
+	}
+	return "", fmt.Errorf("document: unrecognized image content (not png/jpeg/gif/tiff/svg)")
+}
+
+func isTIFF(data []byte) bool {
+	return bytes.HasPrefix(data, []byte("II*\x00")) || bytes.HasPrefix(data, []byte("MM\x00*"))
+}
+
+// looksLikeSVG reports whether data's head contains an <svg root element,
+// tolerating a leading XML prolog/doctype/comments before it.
+func looksLikeSVG(data []byte) bool {
+	head := data
+	if len(head) > 512 {
+		head = head[:512]
+	}
+	return bytes.Contains(bytes.ToLower(head), []byte("<svg"))
+}
+
+// rasterGeometry converts a raster image's pixel dimensions to an
+// imageGeometry, treating the pixel grid as its intrinsic size at 96 DPI.
+func rasterGeometry(widthPx, heightPx int) imageGeometry {
+	return imageGeometry{
+		aspect:             float64(widthPx) / float64(heightPx),
+		intrinsicWidthEmu:  widthPx * 9525,
+		intrinsicHeightEmu: heightPx * 9525,
+	}
+}
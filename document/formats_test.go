@@ -0,0 +1,87 @@
+package document
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+// buildTIFF assembles a minimal single-IFD TIFF with ImageWidth (256) and
+// ImageLength (257) SHORT entries, in the given byte order.
+func buildTIFF(t *testing.T, order binary.ByteOrder, width, height uint16) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if order == binary.LittleEndian {
+		buf.WriteString("II")
+	} else {
+		buf.WriteString("MM")
+	}
+	binary.Write(&buf, order, uint16(42))
+	binary.Write(&buf, order, uint32(8)) // IFD offset
+	binary.Write(&buf, order, uint16(2)) // entry count
+
+	writeEntry := func(tag uint16, value uint16) {
+		binary.Write(&buf, order, tag)
+		binary.Write(&buf, order, uint16(3)) // SHORT
+		binary.Write(&buf, order, uint32(1)) // count
+		binary.Write(&buf, order, value)
+		binary.Write(&buf, order, uint16(0)) // pad to 4 bytes
+	}
+	writeEntry(256, width)
+	writeEntry(257, height)
+	binary.Write(&buf, order, uint32(0)) // next IFD offset
+	return buf.Bytes()
+}
+
+func TestDecodeTIFFSize(t *testing.T) {
+	for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		data := buildTIFF(t, order, 64, 32)
+		w, h, err := decodeTIFFSize(data)
+		if err != nil {
+			t.Fatalf("decodeTIFFSize: %v", err)
+		}
+		if w != 64 || h != 32 {
+			t.Errorf("got %dx%d, want 64x32", w, h)
+		}
+	}
+}
+
+func TestDecodeTIFFSizeTruncated(t *testing.T) {
+	if _, _, err := decodeTIFFSize([]byte("II*\x00")); err == nil {
+		t.Error("expected error for truncated TIFF data")
+	}
+}
+
+func TestSniffImageMIMETIFF(t *testing.T) {
+	data := buildTIFF(t, binary.LittleEndian, 1, 1)
+	mime, err := sniffImageMIME(data)
+	if err != nil {
+		t.Fatalf("sniffImageMIME: %v", err)
+	}
+	if mime != "image/tiff" {
+		t.Errorf("got %q, want image/tiff", mime)
+	}
+}
+
+func TestSniffImageMIMESVG(t *testing.T) {
+	data := []byte(`<?xml version="1.0"?><svg xmlns="http://www.w3.org/2000/svg"></svg>`)
+	mime, err := sniffImageMIME(data)
+	if err != nil {
+		t.Fatalf("sniffImageMIME: %v", err)
+	}
+	if mime != "image/svg+xml" {
+		t.Errorf("got %q, want image/svg+xml", mime)
+	}
+}
+
+func TestSniffImageMIMEUnrecognizedRejected(t *testing.T) {
+	cases := [][]byte{
+		[]byte("this is just plain text, not an image at all"),
+		{0x00, 0x01, 0x02, 0x03, 0x04},
+	}
+	for _, data := range cases {
+		if _, err := sniffImageMIME(data); err == nil {
+			t.Errorf("sniffImageMIME(%q) should have been rejected, not assumed SVG", data)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package document
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+)
+
+// Rasterizer renders SVG source into a PNG-encoded image of the given pixel
+// dimensions. It is the extension point used to produce the raster fallback
+// required by the MS OOXML SVG extension (older Word/LibreOffice versions
+// render the PNG and ignore the embedded SVG).
+type Rasterizer func(svgData []byte, widthPx, heightPx int) ([]byte, error)
+
+// DefaultRasterizer is used when an Image's content sniffs as SVG. It does
+// not parse SVG markup; it produces a plain white canvas of the requested
+// size so that a PNG fallback part always exists. Callers that need faithful
+// rasterization should set DefaultRasterizer to one backed by a real SVG
+// renderer (e.g. github.com/srwiley/oksvg + rasterx) before calling Build.
+var DefaultRasterizer Rasterizer = rasterizeBlankPNG
+
+func rasterizeBlankPNG(svgData []byte, widthPx, heightPx int) ([]byte, error) {
+	if widthPx <= 0 {
+		widthPx = 1
+	}
+	if heightPx <= 0 {
+		heightPx = 1
+	}
+	img := image.NewRGBA(image.Rect(0, 0, widthPx, heightPx))
+	fill := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	for y := 0; y < heightPx; y++ {
+		for x := 0; x < widthPx; x++ {
+			img.Set(x, y, fill)
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("error encoding PNG fallback: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// emuToPx converts EMUs to pixels at 96 DPI (1 px = 9525 EMU).
+func emuToPx(emu int) int {
+	return emu / 9525
+}
+
+// mediaAsset is one unique image figure as written into word/media: the
+// bytes and format of its primary part (relID is its blip target), plus, for
+// SVGs only, the vector source alongside the rasterized PNG fallback held in
+// data (svgRelID is the asvg:svgBlip extension's target).
+type mediaAsset struct {
+	format ImageFormat
+	data   []byte
+	relID  string
+
+	svgData  []byte
+	svgRelID string
+}
+
+// mediaLibrary deduplicates figures by the SHA-256 of their file bytes so
+// that repeated figures share a single set of media parts and relationships
+// rather than being written out twice.
+type mediaLibrary struct {
+	assets      []mediaAsset
+	hashToAsset map[[sha256.Size]byte]int // 0-based index into assets
+	nextRelID   int
+}
+
+func newMediaLibrary() *mediaLibrary {
+	return &mediaLibrary{hashToAsset: make(map[[sha256.Size]byte]int), nextRelID: 1}
+}
+
+// add registers an image's file bytes, sniffing its format and dispatching
+// to the matching ImageFormats handler (or, for SVG, rasterizing a PNG
+// fallback at widthPx x heightPx) only the first time this content is seen.
+// It returns the asset, whose relID(s) are used as r:embed targets.
+func (m *mediaLibrary) add(data []byte, widthPx, heightPx int) (*mediaAsset, error) {
+	hash := sha256.Sum256(data)
+	if idx, ok := m.hashToAsset[hash]; ok {
+		return &m.assets[idx], nil
+	}
+
+	var asset mediaAsset
+	mime, err := sniffImageMIME(data)
+	if err != nil {
+		return nil, err
+	}
+	if mime == "image/svg+xml" {
+		pngData, err := DefaultRasterizer(data, widthPx, heightPx)
+		if err != nil {
+			return nil, fmt.Errorf("error rasterizing PNG fallback: %v", err)
+		}
+		asset = mediaAsset{
+			format:   ImageFormat{Extension: "png", ContentType: "image/png"},
+			data:     pngData,
+			relID:    m.allocRelID(),
+			svgData:  data,
+			svgRelID: m.allocRelID(),
+		}
+	} else {
+		format, ok := ImageFormats[mime]
+		if !ok {
+			return nil, fmt.Errorf("no registered ImageFormat for MIME type %q", mime)
+		}
+		asset = mediaAsset{format: format, data: data, relID: m.allocRelID()}
+	}
+
+	m.assets = append(m.assets, asset)
+	m.hashToAsset[hash] = len(m.assets) - 1
+	return &m.assets[len(m.assets)-1], nil
+}
+
+func (m *mediaLibrary) allocRelID() string {
+	id := fmt.Sprintf("rId%d", m.nextRelID)
+	m.nextRelID++
+	return id
+}
+
+func (m *mediaLibrary) count() int { return len(m.assets) }
@@ -0,0 +1,69 @@
+package document
+
+import "testing"
+
+func TestMediaLibraryAddDeduplicatesByContent(t *testing.T) {
+	lib := newMediaLibrary()
+	png := []byte("\x89PNG\r\n\x1a\nrest-of-a-fake-png")
+
+	first, err := lib.add(png, 10, 10)
+	if err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	second, err := lib.add(append([]byte(nil), png...), 10, 10)
+	if err != nil {
+		t.Fatalf("add: %v", err)
+	}
+
+	if first != second {
+		t.Errorf("identical content should return the same *mediaAsset, got distinct assets")
+	}
+	if lib.count() != 1 {
+		t.Errorf("got %d assets, want 1 after adding identical content twice", lib.count())
+	}
+}
+
+func TestMediaLibraryAddDistinctContentGetsDistinctRelIDs(t *testing.T) {
+	lib := newMediaLibrary()
+	pngA := []byte("\x89PNG\r\n\x1a\nfirst")
+	pngB := []byte("\x89PNG\r\n\x1a\nsecond")
+
+	assetA, err := lib.add(pngA, 10, 10)
+	if err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	assetB, err := lib.add(pngB, 10, 10)
+	if err != nil {
+		t.Fatalf("add: %v", err)
+	}
+
+	if assetA.relID == assetB.relID {
+		t.Errorf("distinct content should get distinct relIDs, both got %q", assetA.relID)
+	}
+	if lib.count() != 2 {
+		t.Errorf("got %d assets, want 2", lib.count())
+	}
+}
+
+func TestMediaLibraryAddSVGGetsTwoRelIDs(t *testing.T) {
+	lib := newMediaLibrary()
+	svg := []byte(`<svg xmlns="http://www.w3.org/2000/svg" width="10" height="10"></svg>`)
+
+	asset, err := lib.add(svg, 10, 10)
+	if err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if asset.svgData == nil {
+		t.Fatal("expected svgData to be set for an SVG asset")
+	}
+	if asset.relID == asset.svgRelID {
+		t.Errorf("PNG fallback and SVG blip should use distinct relIDs, both got %q", asset.relID)
+	}
+}
+
+func TestMediaLibraryAddRejectsUnrecognizedContent(t *testing.T) {
+	lib := newMediaLibrary()
+	if _, err := lib.add([]byte("plain text, not an image"), 10, 10); err == nil {
+		t.Error("expected an error for unrecognized content")
+	}
+}
@@ -0,0 +1,68 @@
+package document
+
+// PageSize selects a standard paper size for a Document's sectPr.
+type PageSize int
+
+const (
+	PageSizeA4 PageSize = iota
+	PageSizeLetter
+)
+
+// Orientation selects a Document's page orientation.
+type Orientation int
+
+const (
+	Portrait Orientation = iota
+	Landscape
+)
+
+// Margins are page margins in twips (1/20 of a point).
+type Margins struct {
+	Top    int
+	Right  int
+	Bottom int
+	Left   int
+}
+
+// DocxOptions is the page geometry used to render a Document: paper size,
+// orientation, and margins. It replaces the fixed A4-portrait-1in-margins
+// layout the original flat renderer assumed.
+type DocxOptions struct {
+	PageSize    PageSize
+	Orientation Orientation
+	Margins     Margins
+}
+
+// DefaultDocxOptions is A4 portrait with 1 inch (1440 twip) margins on all
+// sides, matching the page geometry the package used before DocxOptions
+// existed.
+func DefaultDocxOptions() DocxOptions {
+	return DocxOptions{
+		PageSize:    PageSizeA4,
+		Orientation: Portrait,
+		Margins:     Margins{Top: 1440, Right: 1440, Bottom: 1440, Left: 1440},
+	}
+}
+
+// pageDimsTwips returns the page width and height in twips, swapped for
+// landscape orientation.
+func (o DocxOptions) pageDimsTwips() (width, height int) {
+	switch o.PageSize {
+	case PageSizeLetter:
+		width, height = 12240, 15840
+	default:
+		width, height = 11906, 16838
+	}
+	if o.Orientation == Landscape {
+		width, height = height, width
+	}
+	return width, height
+}
+
+// usableWidthEmu returns the page width minus left/right margins, converted
+// from twips to EMUs (1 twip = 1/20 point, 1 point = 12700 EMU).
+func (o DocxOptions) usableWidthEmu() int {
+	width, _ := o.pageDimsTwips()
+	usableTwips := width - o.Margins.Left - o.Margins.Right
+	return usableTwips / 20 * 12700
+}
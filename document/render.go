@@ -0,0 +1,334 @@
+package document
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+)
+
+// xmlEscape escapes special XML characters for use inside element text.
+func xmlEscape(s string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
+
+// hyperlinkRel is one external hyperlink relationship discovered while
+// rendering the body.
+type hyperlinkRel struct {
+	id  string
+	url string
+}
+
+// renderer carries the state threaded through a single Document render: the
+// media library for image dedup, the page geometry used for default image
+// sizing, and the hyperlink relationships discovered along the way.
+type renderer struct {
+	lib                *mediaLibrary
+	pageUsableWidthEmu int
+	hyperlinks         []hyperlinkRel
+	nextDocPrID        int
+}
+
+func newRenderer(pageUsableWidthEmu int) *renderer {
+	return &renderer{lib: newMediaLibrary(), pageUsableWidthEmu: pageUsableWidthEmu, nextDocPrID: 1}
+}
+
+// renderBody renders every block to the w:body's inner XML.
+func (r *renderer) renderBody(blocks []Block) (string, error) {
+	var body bytes.Buffer
+	for _, block := range blocks {
+		blockXML, err := r.renderBlock(block)
+		if err != nil {
+			return "", err
+		}
+		body.WriteString(blockXML)
+	}
+	return body.String(), nil
+}
+
+func (r *renderer) renderBlock(block Block) (string, error) {
+	switch b := block.(type) {
+	case *Heading:
+		return r.renderHeading(b), nil
+	case *Paragraph:
+		return r.renderParagraph(b), nil
+	case *List:
+		return r.renderList(b), nil
+	case *Table:
+		return r.renderTable(b), nil
+	case *Image:
+		return r.renderImage(b)
+	default:
+		return "", fmt.Errorf("document: unknown block type %T", block)
+	}
+}
+
+func (r *renderer) renderHeading(h *Heading) string {
+	level := h.Level
+	if level < 1 {
+		level = 1
+	}
+	if level > 6 {
+		level = 6
+	}
+	return fmt.Sprintf(`
+    <w:p>
+      <w:pPr>
+        <w:pStyle w:val="Heading%d"/>
+      </w:pPr>
+      <w:r>
+        <w:t>%s</w:t>
+      </w:r>
+    </w:p>`, level, xmlEscape(h.Text))
+}
+
+func runXML(run Run) string {
+	var rPr bytes.Buffer
+	if run.Bold {
+		rPr.WriteString("<w:b/>")
+	}
+	if run.Italic {
+		rPr.WriteString("<w:i/>")
+	}
+	if run.Underline {
+		rPr.WriteString(`<w:u w:val="single"/>`)
+	}
+	if run.Color != "" {
+		rPr.WriteString(fmt.Sprintf(`<w:color w:val="%s"/>`, run.Color))
+	}
+	if run.SizeHalfPoints > 0 {
+		rPr.WriteString(fmt.Sprintf(`<w:sz w:val="%d"/>`, run.SizeHalfPoints))
+	}
+	rPrXML := ""
+	if rPr.Len() > 0 {
+		rPrXML = "<w:rPr>" + rPr.String() + "</w:rPr>"
+	}
+	return fmt.Sprintf(`
+      <w:r>%s
+        <w:t xml:space="preserve">%s</w:t>
+      </w:r>`, rPrXML, xmlEscape(run.Text))
+}
+
+func (r *renderer) hyperlinkXML(h Hyperlink) string {
+	id := fmt.Sprintf("rIdHlink%d", len(r.hyperlinks)+1)
+	r.hyperlinks = append(r.hyperlinks, hyperlinkRel{id: id, url: h.URL})
+	return fmt.Sprintf(`
+      <w:hyperlink r:id="%s" w:history="1">
+        <w:r>
+          <w:rPr>
+            <w:color w:val="0563C1"/>
+            <w:u w:val="single"/>
+          </w:rPr>
+          <w:t xml:space="preserve">%s</w:t>
+        </w:r>
+      </w:hyperlink>`, id, xmlEscape(h.Text))
+}
+
+func (r *renderer) renderParagraph(p *Paragraph) string {
+	var inner bytes.Buffer
+	for _, run := range p.Runs {
+		inner.WriteString(runXML(run))
+	}
+	for _, link := range p.Hyperlinks {
+		inner.WriteString(r.hyperlinkXML(link))
+	}
+	return fmt.Sprintf(`
+    <w:p>%s
+    </w:p>`, inner.String())
+}
+
+func (r *renderer) renderList(l *List) string {
+	var body bytes.Buffer
+	numID := numIDForList(l.Ordered)
+	var renderItems func(items []ListItem, level int)
+	renderItems = func(items []ListItem, level int) {
+		for _, item := range items {
+			body.WriteString(fmt.Sprintf(`
+    <w:p>
+      <w:pPr>
+        <w:numPr>
+          <w:ilvl w:val="%d"/>
+          <w:numId w:val="%d"/>
+        </w:numPr>
+      </w:pPr>
+      <w:r>
+        <w:t>%s</w:t>
+      </w:r>
+    </w:p>`, level, numID, xmlEscape(item.Text)))
+			renderItems(item.Children, level+1)
+		}
+	}
+	renderItems(l.Items, 0)
+	return body.String()
+}
+
+func (r *renderer) renderTable(t *Table) string {
+	var buf bytes.Buffer
+	buf.WriteString("\n    <w:tbl>")
+	borders := ""
+	if t.Borders {
+		borders = `
+        <w:tblBorders>
+          <w:top w:val="single" w:sz="4" w:color="auto"/>
+          <w:left w:val="single" w:sz="4" w:color="auto"/>
+          <w:bottom w:val="single" w:sz="4" w:color="auto"/>
+          <w:right w:val="single" w:sz="4" w:color="auto"/>
+          <w:insideH w:val="single" w:sz="4" w:color="auto"/>
+          <w:insideV w:val="single" w:sz="4" w:color="auto"/>
+        </w:tblBorders>`
+	}
+	buf.WriteString(fmt.Sprintf(`
+      <w:tblPr>
+        <w:tblW w:w="%d" w:type="dxa"/>%s
+      </w:tblPr>`, r.pageUsableWidthEmuToTwips(), borders))
+
+	for _, row := range t.Rows {
+		buf.WriteString("\n      <w:tr>")
+		colWidth := 0
+		if len(row.Cells) > 0 {
+			colWidth = r.pageUsableWidthEmuToTwips() / len(row.Cells)
+		}
+		for _, cell := range row.Cells {
+			width := cell.WidthTwips
+			if width == 0 {
+				width = colWidth
+			}
+			buf.WriteString(fmt.Sprintf(`
+        <w:tc>
+          <w:tcPr>
+            <w:tcW w:w="%d" w:type="dxa"/>
+          </w:tcPr>
+          <w:p>
+            <w:r>
+              <w:t>%s</w:t>
+            </w:r>
+          </w:p>
+        </w:tc>`, width, xmlEscape(cell.Text)))
+		}
+		buf.WriteString("\n      </w:tr>")
+	}
+	buf.WriteString("\n    </w:tbl>")
+	return buf.String()
+}
+
+// pageUsableWidthEmuToTwips converts the renderer's page usable width from
+// EMUs to twips (1 twip = 635 EMU) for table/cell width attributes, which are
+// expressed in twips rather than EMUs.
+func (r *renderer) pageUsableWidthEmuToTwips() int {
+	return r.pageUsableWidthEmu / 635
+}
+
+func (r *renderer) renderImage(img *Image) (string, error) {
+	data, err := ioutil.ReadFile(img.Path)
+	if err != nil {
+		return "", fmt.Errorf("error reading image file %q: %v", img.Path, err)
+	}
+
+	mime, err := sniffImageMIME(data)
+	if err != nil {
+		return "", fmt.Errorf("error reading image file %q: %v", img.Path, err)
+	}
+	var geom imageGeometry
+	if mime == "image/svg+xml" {
+		geom = parseSVGGeometry(data)
+	} else {
+		format, ok := ImageFormats[mime]
+		if !ok {
+			return "", fmt.Errorf("no registered ImageFormat for %q (%s)", img.Path, mime)
+		}
+		widthPx, heightPx, err := format.DecodeSize(data)
+		if err != nil {
+			return "", fmt.Errorf("error decoding image file %q: %v", img.Path, err)
+		}
+		geom = rasterGeometry(widthPx, heightPx)
+	}
+	widthEmu, heightEmu := resolveImageSize(geom, img, r.pageUsableWidthEmu)
+
+	asset, err := r.lib.add(data, emuToPx(widthEmu), emuToPx(heightEmu))
+	if err != nil {
+		return "", err
+	}
+
+	docPrID := r.nextDocPrID
+	r.nextDocPrID++
+
+	pPr := ""
+	if jc := jcForAlignment(img.Alignment); jc != "" {
+		pPr = fmt.Sprintf(`
+      <w:pPr>
+        <w:jc w:val="%s"/>
+      </w:pPr>`, jc)
+	}
+
+	return fmt.Sprintf(`
+    <w:p>%s
+      <w:r>
+        <w:drawing>
+          <wp:inline distT="0" distB="0" distL="0" distR="0">
+            <wp:extent cx="%d" cy="%d"/>
+            <wp:effectExtent l="0" t="0" r="0" b="0"/>
+            <wp:docPr id="%d" name="Picture %d"/>
+            <wp:cNvGraphicFramePr>
+              <a:graphicFrameLocks noChangeAspect="1"/>
+            </wp:cNvGraphicFramePr>
+            <a:graphic>
+              <a:graphicData uri="http://schemas.openxmlformats.org/drawingml/2006/picture">
+                <pic:pic>
+                  <pic:nvPicPr>
+                    <pic:cNvPr id="0" name="Picture %d"/>
+                    <pic:cNvPicPr/>
+                  </pic:nvPicPr>
+                  <pic:blipFill>
+                    %s
+                    <a:stretch>
+                      <a:fillRect/>
+                    </a:stretch>
+                  </pic:blipFill>
+                  <pic:spPr>
+                    <a:xfrm>
+                      <a:off x="0" y="0"/>
+                      <a:ext cx="%d" cy="%d"/>
+                    </a:xfrm>
+                    <a:prstGeom prst="rect">
+                      <a:avLst/>
+                    </a:prstGeom>
+                  </pic:spPr>
+                </pic:pic>
+              </a:graphicData>
+            </a:graphic>
+          </wp:inline>
+        </w:drawing>
+      </w:r>
+    </w:p>`, pPr, widthEmu, heightEmu, docPrID, docPrID, docPrID, blipXML(asset), widthEmu, heightEmu), nil
+}
+
+// blipXML renders the a:blip element for asset: a plain blip for raster
+// formats, or a blip with the asvg:svgBlip extension carrying the vector
+// source alongside the rasterized PNG fallback for SVG figures.
+func blipXML(asset *mediaAsset) string {
+	if asset.svgData == nil {
+		return fmt.Sprintf(`<a:blip r:embed="%s"/>`, asset.relID)
+	}
+	return fmt.Sprintf(`<a:blip r:embed="%s">
+                      <a:extLst>
+                        <a:ext uri="{96DAC541-7B7A-43D3-8B79-37D633B846F1}">
+                          <asvg:svgBlip r:embed="%s"/>
+                        </a:ext>
+                      </a:extLst>
+                    </a:blip>`, asset.relID, asset.svgRelID)
+}
+
+// jcForAlignment returns the w:jc value for an Alignment, or "" for
+// AlignLeft (the default, which needs no explicit w:jc).
+func jcForAlignment(a Alignment) string {
+	switch a {
+	case AlignCenter:
+		return "center"
+	case AlignRight:
+		return "right"
+	default:
+		return ""
+	}
+}
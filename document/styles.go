@@ -0,0 +1,104 @@
+package document
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// stylesXML returns the contents of word/styles.xml: the document defaults
+// plus the six built-in heading styles used by Heading blocks.
+func stylesXML() string {
+	var buf bytes.Buffer
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">
+  <w:docDefaults>
+    <w:rPrDefault>
+      <w:rPr>
+        <w:sz w:val="22"/>
+      </w:rPr>
+    </w:rPrDefault>
+  </w:docDefaults>
+  <w:style w:type="paragraph" w:default="1" w:styleId="Normal">
+    <w:name w:val="Normal"/>
+  </w:style>`)
+
+	// Heading1-6: decreasing font sizes, bold, same family of style ids
+	// referenced by w:pStyle in rendered Heading blocks.
+	headingSizes := [6]int{32, 28, 26, 24, 22, 22}
+	for level := 1; level <= 6; level++ {
+		buf.WriteString(fmt.Sprintf(`
+  <w:style w:type="paragraph" w:styleId="Heading%d">
+    <w:name w:val="heading %d"/>
+    <w:basedOn w:val="Normal"/>
+    <w:pPr>
+      <w:outlineLvl w:val="%d"/>
+    </w:pPr>
+    <w:rPr>
+      <w:b/>
+      <w:sz w:val="%d"/>
+    </w:rPr>
+  </w:style>`, level, level, level-1, headingSizes[level-1]))
+	}
+	buf.WriteString("\n</w:styles>")
+	return buf.String()
+}
+
+// numberingXML returns the contents of word/numbering.xml: one abstract
+// numbering definition for unordered (bullet) lists and one for ordered
+// (decimal) lists, each usable up to maxListLevels deep, plus the two
+// concrete w:num instances List rendering refers to via numIDForList.
+func numberingXML() string {
+	var buf bytes.Buffer
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:numbering xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">`)
+
+	buf.WriteString(abstractNumXML(0, false))
+	buf.WriteString(abstractNumXML(1, true))
+
+	buf.WriteString(`
+  <w:num w:numId="1">
+    <w:abstractNumId w:val="0"/>
+  </w:num>
+  <w:num w:numId="2">
+    <w:abstractNumId w:val="1"/>
+  </w:num>
+</w:numbering>`)
+	return buf.String()
+}
+
+// maxListLevels bounds how deep List.Items may nest; it matches the number
+// of w:lvl definitions generated per abstract numbering.
+const maxListLevels = 9
+
+func abstractNumXML(abstractNumID int, ordered bool) string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf(`
+  <w:abstractNum w:abstractNumId="%d">`, abstractNumID))
+	for level := 0; level < maxListLevels; level++ {
+		numFmt, lvlText := "bullet", "•"
+		if ordered {
+			numFmt, lvlText = "decimal", fmt.Sprintf("%%%d.", level+1)
+		}
+		buf.WriteString(fmt.Sprintf(`
+    <w:lvl w:ilvl="%d">
+      <w:start w:val="1"/>
+      <w:numFmt w:val="%s"/>
+      <w:lvlText w:val="%s"/>
+      <w:pPr>
+        <w:ind w:left="%d" w:hanging="360"/>
+      </w:pPr>
+    </w:lvl>`, level, numFmt, lvlText, 720*(level+1)))
+	}
+	buf.WriteString(`
+  </w:abstractNum>`)
+	return buf.String()
+}
+
+// numIDForList returns the word/numbering.xml w:numId a List should
+// reference for its w:numPr.
+func numIDForList(ordered bool) int {
+	if ordered {
+		return 2
+	}
+	return 1
+}
@@ -0,0 +1,157 @@
+package document
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strconv"
+	"strings"
+)
+
+// emuPerUnit maps the CSS length units that may appear in an SVG root
+// element's width/height attributes to their EMU equivalents. Percentages
+// are deliberately excluded: they have no absolute size without a containing
+// viewport.
+var emuPerUnit = map[string]float64{
+	"px": 9525,
+	"pt": 12700,
+	"in": 914400,
+	"cm": 360000,
+	"mm": 36000,
+}
+
+// parseLengthEmu converts an SVG length attribute (e.g. "300px", "21cm",
+// "8.5in", or a unitless number, which SVG treats as px) to EMUs. ok is false
+// for percentages or values that don't parse.
+func parseLengthEmu(s string) (emu int, ok bool) {
+	s = strings.TrimSpace(s)
+	if s == "" || strings.HasSuffix(s, "%") {
+		return 0, false
+	}
+	for unit, perUnit := range emuPerUnit {
+		if strings.HasSuffix(s, unit) {
+			val, err := strconv.ParseFloat(strings.TrimSuffix(s, unit), 64)
+			if err != nil {
+				return 0, false
+			}
+			return int(val * perUnit), true
+		}
+	}
+	val, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, false
+	}
+	return int(val * emuPerUnit["px"]), true
+}
+
+// legacyAspect is the 3000000x2000000 EMU source size the renderer assumed
+// before SVGs were inspected for their own geometry; it remains the fallback
+// when an SVG's root element has neither a viewBox nor width/height.
+const legacyAspect = 3000000.0 / 2000000.0
+
+// imageGeometry is an image's aspect ratio and, when known, its intrinsic
+// physical size in EMUs. parseSVGGeometry recovers it from an SVG's root
+// element; raster formats derive it directly from decoded pixel dimensions.
+type imageGeometry struct {
+	aspect             float64
+	intrinsicWidthEmu  int
+	intrinsicHeightEmu int
+}
+
+// parseSVGGeometry reads just enough of svgData's root <svg> element to
+// recover its aspect ratio and, when available, its intrinsic physical size.
+// It never returns an error: malformed or unrecognized SVGs fall back to the
+// legacy fixed aspect ratio so callers can always lay out a picture.
+func parseSVGGeometry(svgData []byte) imageGeometry {
+	decoder := xml.NewDecoder(bytes.NewReader(svgData))
+	for {
+		tok, err := decoder.Token()
+		if err != nil {
+			break
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "svg" {
+			continue
+		}
+
+		var widthAttr, heightAttr, viewBoxAttr string
+		for _, attr := range start.Attr {
+			switch attr.Name.Local {
+			case "width":
+				widthAttr = attr.Value
+			case "height":
+				heightAttr = attr.Value
+			case "viewBox":
+				viewBoxAttr = attr.Value
+			}
+		}
+
+		widthEmu, widthOK := parseLengthEmu(widthAttr)
+		heightEmu, heightOK := parseLengthEmu(heightAttr)
+
+		geom := imageGeometry{aspect: legacyAspect}
+		if widthOK && heightOK {
+			geom.intrinsicWidthEmu, geom.intrinsicHeightEmu = widthEmu, heightEmu
+		}
+
+		if vbW, vbH, ok := parseViewBoxRatio(viewBoxAttr); ok {
+			geom.aspect = vbW / vbH
+		} else if widthOK && heightOK && heightEmu != 0 {
+			geom.aspect = float64(widthEmu) / float64(heightEmu)
+		}
+		return geom
+	}
+	return imageGeometry{aspect: legacyAspect}
+}
+
+// resolveImageSize picks the final EMU extent for an Image given its
+// decoded geometry and the page's usable width. Explicit
+// Image.WidthEmu/HeightEmu win outright; otherwise the image's own intrinsic
+// physical size is honored (downscaled to MaxWidthEmu/the page width if it
+// doesn't fit), falling back to scaling the aspect ratio to fill that width
+// when the image has no absolute size of its own.
+func resolveImageSize(geom imageGeometry, img *Image, pageUsableWidthEmu int) (widthEmu, heightEmu int) {
+	aspect := geom.aspect
+	if aspect <= 0 {
+		aspect = legacyAspect
+	}
+
+	switch {
+	case img.WidthEmu > 0 && img.HeightEmu > 0:
+		return img.WidthEmu, img.HeightEmu
+	case img.WidthEmu > 0:
+		return img.WidthEmu, int(float64(img.WidthEmu) / aspect)
+	case img.HeightEmu > 0:
+		return int(float64(img.HeightEmu) * aspect), img.HeightEmu
+	}
+
+	maxWidthEmu := img.MaxWidthEmu
+	if maxWidthEmu <= 0 {
+		maxWidthEmu = pageUsableWidthEmu
+	}
+
+	if geom.intrinsicWidthEmu > 0 && geom.intrinsicWidthEmu <= maxWidthEmu {
+		return geom.intrinsicWidthEmu, geom.intrinsicHeightEmu
+	}
+
+	widthEmu = maxWidthEmu
+	heightEmu = int(float64(widthEmu) / aspect)
+	return widthEmu, heightEmu
+}
+
+// parseViewBoxRatio parses an SVG viewBox="minX minY width height" attribute
+// and returns its width/height ratio components.
+func parseViewBoxRatio(viewBox string) (width, height float64, ok bool) {
+	fields := strings.Fields(viewBox)
+	if len(fields) != 4 {
+		return 0, 0, false
+	}
+	width, err := strconv.ParseFloat(fields[2], 64)
+	if err != nil || width <= 0 {
+		return 0, 0, false
+	}
+	height, err = strconv.ParseFloat(fields[3], 64)
+	if err != nil || height <= 0 {
+		return 0, 0, false
+	}
+	return width, height, true
+}
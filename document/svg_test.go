@@ -0,0 +1,99 @@
+package document
+
+import "testing"
+
+func TestParseLengthEmu(t *testing.T) {
+	cases := []struct {
+		in     string
+		want   int
+		wantOK bool
+	}{
+		{"300px", 300 * 9525, true},
+		{"21cm", 21 * 360000, true},
+		{"8.5in", int(8.5 * 914400), true},
+		{"72pt", 72 * 12700, true},
+		{"50mm", 50 * 36000, true},
+		{"200", 200 * 9525, true}, // unitless treated as px
+		{"50%", 0, false},
+		{"not-a-number", 0, false},
+		{"", 0, false},
+	}
+	for _, c := range cases {
+		got, ok := parseLengthEmu(c.in)
+		if ok != c.wantOK {
+			t.Errorf("parseLengthEmu(%q) ok = %v, want %v", c.in, ok, c.wantOK)
+			continue
+		}
+		if ok && got != c.want {
+			t.Errorf("parseLengthEmu(%q) = %d, want %d", c.in, got, c.want)
+		}
+	}
+}
+
+func TestParseViewBoxRatio(t *testing.T) {
+	w, h, ok := parseViewBoxRatio("0 0 200 100")
+	if !ok || w != 200 || h != 100 {
+		t.Fatalf("parseViewBoxRatio(valid) = %v, %v, %v", w, h, ok)
+	}
+	if _, _, ok := parseViewBoxRatio("0 0 200"); ok {
+		t.Errorf("parseViewBoxRatio(too few fields) should fail")
+	}
+	if _, _, ok := parseViewBoxRatio("0 0 -200 100"); ok {
+		t.Errorf("parseViewBoxRatio(non-positive width) should fail")
+	}
+	if _, _, ok := parseViewBoxRatio(""); ok {
+		t.Errorf("parseViewBoxRatio(empty) should fail")
+	}
+}
+
+func TestResolveImageSizeExplicitDimensions(t *testing.T) {
+	geom := imageGeometry{aspect: 2}
+
+	img := &Image{WidthEmu: 1000, HeightEmu: 500}
+	if w, h := resolveImageSize(geom, img, 9144000); w != 1000 || h != 500 {
+		t.Errorf("both explicit: got %d x %d, want 1000 x 500", w, h)
+	}
+
+	img = &Image{WidthEmu: 1000}
+	if w, h := resolveImageSize(geom, img, 9144000); w != 1000 || h != 500 {
+		t.Errorf("width only: got %d x %d, want 1000 x 500 (derived from aspect)", w, h)
+	}
+
+	img = &Image{HeightEmu: 500}
+	if w, h := resolveImageSize(geom, img, 9144000); w != 1000 || h != 500 {
+		t.Errorf("height only: got %d x %d, want 1000 x 500 (derived from aspect)", w, h)
+	}
+}
+
+func TestResolveImageSizeIntrinsicFitsPage(t *testing.T) {
+	geom := imageGeometry{aspect: 2, intrinsicWidthEmu: 1000, intrinsicHeightEmu: 500}
+	img := &Image{}
+	if w, h := resolveImageSize(geom, img, 9144000); w != 1000 || h != 500 {
+		t.Errorf("intrinsic fits page: got %d x %d, want 1000 x 500", w, h)
+	}
+}
+
+func TestResolveImageSizeScalesToMaxWidth(t *testing.T) {
+	geom := imageGeometry{aspect: 2, intrinsicWidthEmu: 20000000, intrinsicHeightEmu: 10000000}
+	img := &Image{MaxWidthEmu: 1000000}
+	w, h := resolveImageSize(geom, img, 9144000)
+	if w != 1000000 {
+		t.Errorf("got width %d, want MaxWidthEmu 1000000", w)
+	}
+	if h != 500000 {
+		t.Errorf("got height %d, want 500000 (aspect-derived)", h)
+	}
+}
+
+func TestResolveImageSizeNoIntrinsicFillsPageWidth(t *testing.T) {
+	geom := imageGeometry{aspect: legacyAspect}
+	img := &Image{}
+	w, h := resolveImageSize(geom, img, 9144000)
+	if w != 9144000 {
+		t.Errorf("got width %d, want page usable width 9144000", w)
+	}
+	wantH := int(float64(9144000) / legacyAspect)
+	if h != wantH {
+		t.Errorf("got height %d, want %d", h, wantH)
+	}
+}